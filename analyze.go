@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kowalski-ai/api/config"
+	"github.com/kowalski-ai/api/diffchunker"
+	"github.com/kowalski-ai/api/providers"
+	"github.com/kowalski-ai/api/review"
+)
+
+const (
+	defaultMaxChunkTokens = 6000
+	defaultParallelism    = 3
+)
+
+// chunkedAnalysis runs a map-reduce review over diff: it's split into
+// per-file, per-hunk batches under maxChunkTokens, each batch is
+// reviewed independently by a bounded pool of parallelism workers (the
+// "map" pass), and the per-batch reviews are merged into one coherent
+// report by reduceModel (the "reduce" pass). progress, if non-nil, is
+// called with a human-readable status line after each batch completes.
+//
+// policy's IgnoredPaths are dropped before batching, its CustomPrompt
+// is prepended to every batch, and its PreferredModel is used wherever
+// req.ModelType is unset.
+//
+// If the diff fits in a single batch, the reduce pass is skipped and
+// that batch's review is returned directly.
+func chunkedAnalysis(ctx context.Context, router *providers.Router, diff string, req PRAnalysisRequest, policy config.Policy, progress func(string)) (providers.Result, error) {
+	modelType := req.ModelType
+	if modelType == "" {
+		modelType = policy.PreferredModel
+	}
+
+	maxChunkTokens := req.MaxChunkTokens
+	if maxChunkTokens <= 0 {
+		maxChunkTokens = defaultMaxChunkTokens
+	}
+	parallelism := req.Parallelism
+	if parallelism <= 0 {
+		parallelism = defaultParallelism
+	}
+
+	files := diffchunker.FilterIgnored(diffchunker.ParseDiff(diff), policy.IgnoredPaths)
+	batches := diffchunker.PackBatches(files, maxChunkTokens)
+	if len(batches) == 0 {
+		return providers.Result{}, fmt.Errorf("chunkedAnalysis: diff produced no reviewable content")
+	}
+	for i := range batches {
+		if policy.CustomPrompt != "" {
+			batches[i].Content = policy.CustomPrompt + "\n\n" + batches[i].Content
+		}
+	}
+	if len(batches) == 1 {
+		return router.Analyze(ctx, batches[0].Content, modelType)
+	}
+
+	reviews := make([]string, len(batches))
+	providerUsed := make([]string, len(batches))
+	errs := make([]error, len(batches))
+
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, batch diffchunker.Batch) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := router.Analyze(ctx, batch.Content, modelType)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			reviews[i] = result.Analysis
+			providerUsed[i] = result.Provider
+			if progress != nil {
+				progress(fmt.Sprintf("analyzed batch %d/%d (%s) via %s", i+1, len(batches), strings.Join(batch.Files, ", "), result.Provider))
+			}
+		}(i, batch)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return providers.Result{}, fmt.Errorf("chunkedAnalysis: batch %d failed: %w", i, err)
+		}
+	}
+
+	reduceModel := req.ReduceModel
+	if reduceModel == "" {
+		reduceModel = modelType
+	}
+
+	var combined strings.Builder
+	combined.WriteString("The following are independent reviews of different parts of the same pull request, analyzed separately because the full diff was too large for one pass. Merge them into a single coherent review, deduplicating overlapping findings and preserving file/line citations:\n\n")
+	for i, review := range reviews {
+		fmt.Fprintf(&combined, "--- Review of batch %d (files: %s, provider: %s) ---\n%s\n\n", i+1, strings.Join(batches[i].Files, ", "), providerUsed[i], review)
+	}
+
+	if progress != nil {
+		progress(fmt.Sprintf("reducing %d batch reviews into final report", len(batches)))
+	}
+
+	return router.Analyze(ctx, combined.String(), reduceModel)
+}
+
+// chunkedStructuredAnalysis is chunkedAnalysis's structured-output
+// counterpart: it runs the same map-reduce batching over diff, but
+// each batch (and the final reduce pass) goes through
+// Router.AnalyzeStructured instead of Analyze, so callers that need a
+// review.Review - the JSON API's ?format=json branch and the webhook
+// handler - get the same large-PR protection as the plain-text path
+// instead of sending an unchunked diff straight to the model.
+//
+// maxChunkTokens and parallelism fall back to defaultMaxChunkTokens and
+// defaultParallelism when zero. If the diff fits in a single batch, the
+// reduce pass is skipped and that batch's review is returned directly.
+func chunkedStructuredAnalysis(ctx context.Context, router *providers.Router, diff, modelType string, maxChunkTokens, parallelism int, policy config.Policy, progress func(string)) (providers.StructuredResult, error) {
+	if maxChunkTokens <= 0 {
+		maxChunkTokens = defaultMaxChunkTokens
+	}
+	if parallelism <= 0 {
+		parallelism = defaultParallelism
+	}
+
+	files := diffchunker.FilterIgnored(diffchunker.ParseDiff(diff), policy.IgnoredPaths)
+	batches := diffchunker.PackBatches(files, maxChunkTokens)
+	if len(batches) == 0 {
+		return providers.StructuredResult{}, fmt.Errorf("chunkedStructuredAnalysis: diff produced no reviewable content")
+	}
+	for i := range batches {
+		if policy.CustomPrompt != "" {
+			batches[i].Content = policy.CustomPrompt + "\n\n" + batches[i].Content
+		}
+	}
+	if len(batches) == 1 {
+		return router.AnalyzeStructured(ctx, batches[0].Content, modelType)
+	}
+
+	reviews := make([]review.Review, len(batches))
+	errs := make([]error, len(batches))
+
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, batch diffchunker.Batch) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := router.AnalyzeStructured(ctx, batch.Content, modelType)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			reviews[i] = result.Review
+			if progress != nil {
+				progress(fmt.Sprintf("analyzed batch %d/%d (%s) via %s", i+1, len(batches), strings.Join(batch.Files, ", "), result.Provider))
+			}
+		}(i, batch)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return providers.StructuredResult{}, fmt.Errorf("chunkedStructuredAnalysis: batch %d failed: %w", i, err)
+		}
+	}
+
+	if progress != nil {
+		progress(fmt.Sprintf("reducing %d batch reviews into final report", len(batches)))
+	}
+
+	encoded, err := json.Marshal(reviews)
+	if err != nil {
+		return providers.StructuredResult{}, fmt.Errorf("chunkedStructuredAnalysis: marshal batch reviews: %w", err)
+	}
+
+	prompt := "The following JSON array holds independent structured reviews of different parts of the same pull request, analyzed separately because the full diff was too large for one pass. Merge them into a single review: deduplicate overlapping findings, keep file/line citations, and let risk_score reflect the most severe finding.\n\n" + string(encoded)
+
+	return router.AnalyzeStructured(ctx, prompt, modelType)
+}
@@ -0,0 +1,64 @@
+// Package config loads process configuration and per-repository review
+// policy once at startup, instead of re-reading .env on every request,
+// and supports reloading both on SIGHUP without a restart.
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+// Config holds process-wide settings sourced from the environment
+// (via .env in development).
+type Config struct {
+	APIKey        string
+	OpenAIKey     string
+	ClaudeKey     string
+	CohereKey     string
+	AzureKey      string
+	AzureEndpoint string
+	LocalModelURL string
+	GithubToken   string
+	Port          string
+	// PolicyPath is the YAML policy file describing per-repo overrides.
+	// Empty disables policy lookups (every repo gets DefaultPolicy()).
+	PolicyPath string
+	// WebhookSecret verifies the X-Hub-Signature-256 header on incoming
+	// GitHub webhook deliveries. Empty disables webhook mode.
+	WebhookSecret string
+	// RouterStrategy selects how the provider router picks among its
+	// healthy providers: "priority" (default), "round_robin", or
+	// "weighted". Empty behaves like "priority".
+	RouterStrategy string
+}
+
+func load() (*Config, error) {
+	// godotenv.Load returning an error (e.g. no .env file present) is
+	// not fatal: in production, config comes from real environment
+	// variables instead.
+	_ = godotenv.Load()
+
+	return &Config{
+		APIKey:         os.Getenv("API_KEY"),
+		OpenAIKey:      os.Getenv("OPENAI_API_KEY"),
+		ClaudeKey:      os.Getenv("CLAUDE_API_KEY"),
+		CohereKey:      os.Getenv("COHERE_API_KEY"),
+		AzureKey:       os.Getenv("AZURE_OPENAI_API_KEY"),
+		AzureEndpoint:  os.Getenv("AZURE_OPENAI_ENDPOINT"),
+		LocalModelURL:  os.Getenv("LOCAL_MODEL_URL"),
+		GithubToken:    os.Getenv("GITHUB_TOKEN"),
+		Port:           os.Getenv("PORT"),
+		PolicyPath:     os.Getenv("POLICY_PATH"),
+		WebhookSecret:  os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		RouterStrategy: os.Getenv("ROUTER_STRATEGY"),
+	}, nil
+}
+
+func validate(c *Config) error {
+	if c.APIKey == "" {
+		return fmt.Errorf("config: API_KEY is required")
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// state is the immutable snapshot swapped in on every (re)load.
+type state struct {
+	config     Config
+	policyFile policyFile
+}
+
+// Manager loads Config and the repo policy file once at startup and
+// keeps them in memory, instead of re-reading .env on every request.
+// Call Watch to pick up SIGHUP and policy-file changes without a
+// restart.
+type Manager struct {
+	current atomic.Pointer[state]
+	watcher *fsnotify.Watcher
+}
+
+// NewManager loads config and policy once and returns a ready Manager.
+func NewManager() (*Manager, error) {
+	m := &Manager{}
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Reload re-reads the environment and policy file and atomically swaps
+// them in. Safe to call concurrently with Config/PolicyFor.
+func (m *Manager) Reload() error {
+	cfg, err := load()
+	if err != nil {
+		return err
+	}
+	if err := validate(cfg); err != nil {
+		return err
+	}
+
+	pf, err := loadPolicyFile(cfg.PolicyPath)
+	if err != nil {
+		return fmt.Errorf("config: loading policy file %q: %w", cfg.PolicyPath, err)
+	}
+
+	m.current.Store(&state{config: *cfg, policyFile: pf})
+	return nil
+}
+
+// Config returns the current configuration snapshot.
+func (m *Manager) Config() Config {
+	return m.current.Load().config
+}
+
+// PolicyFor returns the effective policy for owner/repo: the policy
+// file's default layered with any "owner/repo"-specific override.
+func (m *Manager) PolicyFor(owner, repo string) Policy {
+	s := m.current.Load()
+	override, ok := s.policyFile.Policies[owner+"/"+repo]
+	if !ok {
+		return s.policyFile.Default
+	}
+	return mergePolicy(s.policyFile.Default, override)
+}
+
+// Watch starts background goroutines that reload on SIGHUP and on
+// changes to the policy file, logging (but not panicking on) reload
+// errors. It returns immediately; Stop tears down the policy-file
+// watcher, while the SIGHUP handler stays installed.
+func (m *Manager) Watch() {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	go func() {
+		for range sighup {
+			if err := m.Reload(); err != nil {
+				log.Printf("config: reload on SIGHUP failed: %v", err)
+				continue
+			}
+			log.Printf("config: reloaded on SIGHUP")
+		}
+	}()
+
+	policyPath := m.Config().PolicyPath
+	if policyPath == "" {
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("config: fsnotify unavailable, policy file hot-reload disabled: %v", err)
+		return
+	}
+	if err := watcher.Add(policyPath); err != nil {
+		log.Printf("config: failed to watch policy file %q: %v", policyPath, err)
+		watcher.Close()
+		return
+	}
+	m.watcher = watcher
+
+	go func() {
+		for event := range watcher.Events {
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			if err := m.Reload(); err != nil {
+				log.Printf("config: reload on policy file change failed: %v", err)
+				continue
+			}
+			log.Printf("config: reloaded policy file %q", policyPath)
+		}
+	}()
+}
+
+// Stop tears down the fsnotify watcher started by Watch, if any.
+func (m *Manager) Stop() {
+	if m.watcher != nil {
+		m.watcher.Close()
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy describes per-"owner/repo" review behavior.
+type Policy struct {
+	PreferredModel string   `yaml:"preferred_model"`
+	CustomPrompt   string   `yaml:"custom_prompt"`
+	IgnoredPaths   []string `yaml:"ignored_paths"`
+	MaxDiffSize    int      `yaml:"max_diff_size"`
+}
+
+// DefaultPolicy is applied to any repo without an explicit override.
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxDiffSize: 1 << 20, // 1 MiB
+	}
+}
+
+// policyFile is the on-disk shape of the YAML policy file: a default
+// policy plus per-"owner/repo" overrides layered on top of it.
+type policyFile struct {
+	Default  Policy            `yaml:"default"`
+	Policies map[string]Policy `yaml:"repos"`
+}
+
+func loadPolicyFile(path string) (policyFile, error) {
+	if path == "" {
+		return policyFile{Default: DefaultPolicy()}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return policyFile{}, err
+	}
+
+	parsed := policyFile{Default: DefaultPolicy()}
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return policyFile{}, err
+	}
+	return parsed, nil
+}
+
+// mergePolicy merges override onto base: zero-valued fields in override
+// fall back to base.
+func mergePolicy(base, override Policy) Policy {
+	merged := base
+	if override.PreferredModel != "" {
+		merged.PreferredModel = override.PreferredModel
+	}
+	if override.CustomPrompt != "" {
+		merged.CustomPrompt = override.CustomPrompt
+	}
+	if len(override.IgnoredPaths) > 0 {
+		merged.IgnoredPaths = override.IgnoredPaths
+	}
+	if override.MaxDiffSize > 0 {
+		merged.MaxDiffSize = override.MaxDiffSize
+	}
+	return merged
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMergePolicyOverrideWins(t *testing.T) {
+	base := DefaultPolicy()
+	override := Policy{
+		PreferredModel: "claude",
+		CustomPrompt:   "be terse",
+		IgnoredPaths:   []string{"vendor/"},
+		MaxDiffSize:    42,
+	}
+
+	got := mergePolicy(base, override)
+	if !reflect.DeepEqual(got, override) {
+		t.Errorf("got %+v, want override to fully win: %+v", got, override)
+	}
+}
+
+func TestMergePolicyZeroValuedFieldsFallBackToBase(t *testing.T) {
+	base := Policy{
+		PreferredModel: "openai",
+		CustomPrompt:   "default prompt",
+		IgnoredPaths:   []string{"dist/"},
+		MaxDiffSize:    1 << 20,
+	}
+
+	got := mergePolicy(base, Policy{})
+	if !reflect.DeepEqual(got, base) {
+		t.Errorf("got %+v, want base unchanged: %+v", got, base)
+	}
+}
+
+func TestMergePolicyPartialOverride(t *testing.T) {
+	base := Policy{
+		PreferredModel: "openai",
+		CustomPrompt:   "default prompt",
+		IgnoredPaths:   []string{"dist/"},
+		MaxDiffSize:    1 << 20,
+	}
+
+	got := mergePolicy(base, Policy{PreferredModel: "claude"})
+	want := Policy{
+		PreferredModel: "claude",
+		CustomPrompt:   "default prompt",
+		IgnoredPaths:   []string{"dist/"},
+		MaxDiffSize:    1 << 20,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadPolicyFileEmptyPathReturnsDefault(t *testing.T) {
+	pf, err := loadPolicyFile("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(pf.Default, DefaultPolicy()) {
+		t.Errorf("got default policy %+v, want %+v", pf.Default, DefaultPolicy())
+	}
+	if len(pf.Policies) != 0 {
+		t.Errorf("expected no per-repo overrides, got %v", pf.Policies)
+	}
+}
+
+func TestLoadPolicyFileParsesOverrides(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.yaml")
+	yaml := `
+default:
+  max_diff_size: 2048
+repos:
+  acme/widgets:
+    preferred_model: claude
+    ignored_paths:
+      - vendor/
+`
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	pf, err := loadPolicyFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pf.Default.MaxDiffSize != 2048 {
+		t.Errorf("got default max_diff_size %d, want 2048", pf.Default.MaxDiffSize)
+	}
+	override, ok := pf.Policies["acme/widgets"]
+	if !ok {
+		t.Fatal("expected an override for acme/widgets")
+	}
+	if override.PreferredModel != "claude" {
+		t.Errorf("got preferred_model %q, want %q", override.PreferredModel, "claude")
+	}
+	if !reflect.DeepEqual(override.IgnoredPaths, []string{"vendor/"}) {
+		t.Errorf("got ignored_paths %v, want [vendor/]", override.IgnoredPaths)
+	}
+}
@@ -0,0 +1,55 @@
+package diffchunker
+
+import "fmt"
+
+// Batch is a group of hunks, possibly from several files, packed
+// together under a token budget. Content is the batch's full prompt
+// text, with each hunk prefixed by a "File: <path>" marker so the model
+// can cite locations.
+type Batch struct {
+	Files   []string
+	Content string
+	Tokens  int
+}
+
+// PackBatches packs the hunks in files into batches whose estimated
+// token count stays under maxTokens. A single hunk larger than
+// maxTokens still gets its own batch rather than being dropped.
+func PackBatches(files []FileDiff, maxTokens int) []Batch {
+	var batches []Batch
+	var current Batch
+	var currentFiles map[string]bool
+
+	flush := func() {
+		if current.Tokens == 0 {
+			return
+		}
+		batches = append(batches, current)
+		current = Batch{}
+		currentFiles = nil
+	}
+
+	for _, file := range files {
+		for _, hunk := range file.Hunks {
+			entry := fmt.Sprintf("File: %s (line %d)\n%s", file.Path, hunk.StartLine, hunk.Body)
+			entryTokens := EstimateTokens(entry)
+
+			if current.Tokens > 0 && current.Tokens+entryTokens > maxTokens {
+				flush()
+			}
+
+			if currentFiles == nil {
+				currentFiles = make(map[string]bool)
+			}
+			if !currentFiles[file.Path] {
+				currentFiles[file.Path] = true
+				current.Files = append(current.Files, file.Path)
+			}
+			current.Content += entry + "\n"
+			current.Tokens += entryTokens
+		}
+	}
+	flush()
+
+	return batches
+}
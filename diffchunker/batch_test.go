@@ -0,0 +1,70 @@
+package diffchunker
+
+import "testing"
+
+func mustHunk(startLine int, body string) Hunk {
+	return Hunk{StartLine: startLine, Body: body, Tokens: EstimateTokens(body)}
+}
+
+func TestPackBatchesSingleBatchWhenUnderBudget(t *testing.T) {
+	files := []FileDiff{
+		{Path: "a.go", Hunks: []Hunk{mustHunk(1, "small change")}},
+		{Path: "b.go", Hunks: []Hunk{mustHunk(1, "another small change")}},
+	}
+
+	batches := PackBatches(files, 1000)
+	if len(batches) != 1 {
+		t.Fatalf("got %d batches, want 1", len(batches))
+	}
+	if len(batches[0].Files) != 2 {
+		t.Errorf("got %d files in the batch, want 2", len(batches[0].Files))
+	}
+}
+
+func TestPackBatchesSplitsWhenOverBudget(t *testing.T) {
+	big := make([]byte, 100)
+	for i := range big {
+		big[i] = 'x'
+	}
+	files := []FileDiff{
+		{Path: "a.go", Hunks: []Hunk{mustHunk(1, string(big))}},
+		{Path: "b.go", Hunks: []Hunk{mustHunk(1, string(big))}},
+	}
+
+	// Each hunk's entry is 30 tokens (100 bytes plus the 20-byte
+	// "File: a.go (line 1)\n" marker, / 4); a budget that fits one but
+	// not both forces a second batch.
+	batches := PackBatches(files, 30)
+	if len(batches) != 2 {
+		t.Fatalf("got %d batches, want 2", len(batches))
+	}
+	for _, b := range batches {
+		if b.Tokens > 30 && len(b.Files) > 1 {
+			t.Errorf("batch exceeded budget while holding more than one file: %+v", b)
+		}
+	}
+}
+
+func TestPackBatchesOversizedHunkGetsOwnBatch(t *testing.T) {
+	huge := make([]byte, 1000)
+	for i := range huge {
+		huge[i] = 'x'
+	}
+	files := []FileDiff{
+		{Path: "a.go", Hunks: []Hunk{mustHunk(1, string(huge))}},
+	}
+
+	batches := PackBatches(files, 10)
+	if len(batches) != 1 {
+		t.Fatalf("got %d batches, want 1 (oversized hunk should still ship, not be dropped)", len(batches))
+	}
+	if len(batches[0].Files) != 1 || batches[0].Files[0] != "a.go" {
+		t.Errorf("got files %v, want [a.go]", batches[0].Files)
+	}
+}
+
+func TestPackBatchesEmptyInput(t *testing.T) {
+	if batches := PackBatches(nil, 1000); len(batches) != 0 {
+		t.Errorf("got %d batches for no input, want 0", len(batches))
+	}
+}
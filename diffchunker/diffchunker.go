@@ -0,0 +1,124 @@
+// Package diffchunker splits a unified diff into per-file, per-hunk
+// pieces and packs them into batches that fit under a model's context
+// budget, so large PRs can be reviewed in parallel instead of blowing
+// past a single prompt's token limit.
+package diffchunker
+
+import "strings"
+
+// bytesPerToken is a coarse tiktoken-style heuristic: English code and
+// prose average roughly 4 bytes per token. It avoids pulling in a real
+// tokenizer just to size batches.
+const bytesPerToken = 4
+
+// EstimateTokens returns a rough token count for s.
+func EstimateTokens(s string) int {
+	if len(s) == 0 {
+		return 0
+	}
+	tokens := len(s) / bytesPerToken
+	if tokens == 0 {
+		tokens = 1
+	}
+	return tokens
+}
+
+// Hunk is a single `@@ ... @@` section of a file's diff, kept alongside
+// its starting line so the model can cite locations in its review.
+type Hunk struct {
+	Header    string // the "@@ -a,b +c,d @@" line
+	StartLine int    // the hunk's new-file starting line number
+	Body      string // the hunk header plus its content lines
+	Tokens    int
+}
+
+// FileDiff is one file's worth of hunks from a unified diff.
+type FileDiff struct {
+	Path  string
+	Hunks []Hunk
+}
+
+// ParseDiff splits a unified diff into FileDiffs, each broken down into
+// its constituent hunks.
+func ParseDiff(diff string) []FileDiff {
+	var files []FileDiff
+	var current *FileDiff
+	var hunk *Hunk
+	var body strings.Builder
+
+	flushHunk := func() {
+		if hunk == nil {
+			return
+		}
+		hunk.Body = body.String()
+		hunk.Tokens = EstimateTokens(hunk.Body)
+		current.Hunks = append(current.Hunks, *hunk)
+		hunk = nil
+		body.Reset()
+	}
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flushHunk()
+			if current != nil {
+				files = append(files, *current)
+			}
+			current = &FileDiff{Path: parseDiffGitPath(line)}
+
+		case strings.HasPrefix(line, "@@ "):
+			flushHunk()
+			if current == nil {
+				current = &FileDiff{}
+			}
+			hunk = &Hunk{Header: line, StartLine: parseHunkStartLine(line)}
+			body.WriteString(line)
+			body.WriteString("\n")
+
+		default:
+			if hunk != nil {
+				body.WriteString(line)
+				body.WriteString("\n")
+			}
+		}
+	}
+	flushHunk()
+	if current != nil {
+		files = append(files, *current)
+	}
+
+	return files
+}
+
+// parseDiffGitPath extracts the `b/`-side path from a "diff --git a/x b/x"
+// line, falling back to the raw line if it doesn't match the expected
+// shape.
+func parseDiffGitPath(line string) string {
+	parts := strings.Fields(line)
+	if len(parts) >= 4 {
+		return strings.TrimPrefix(parts[3], "b/")
+	}
+	return line
+}
+
+// parseHunkStartLine pulls the new-file starting line out of a hunk
+// header like "@@ -12,7 +15,9 @@ func foo()". Returns 0 if it can't be
+// parsed.
+func parseHunkStartLine(header string) int {
+	fields := strings.Fields(header)
+	for _, f := range fields {
+		if strings.HasPrefix(f, "+") {
+			numPart := strings.TrimPrefix(f, "+")
+			numPart, _, _ = strings.Cut(numPart, ",")
+			n := 0
+			for _, c := range numPart {
+				if c < '0' || c > '9' {
+					return 0
+				}
+				n = n*10 + int(c-'0')
+			}
+			return n
+		}
+	}
+	return 0
+}
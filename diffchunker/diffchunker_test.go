@@ -0,0 +1,73 @@
+package diffchunker
+
+import "testing"
+
+const twoFileDiff = `diff --git a/foo.go b/foo.go
+@@ -1,2 +1,3 @@
+ package foo
++
+ func Foo() {}
+diff --git a/bar.go b/bar.go
+@@ -10,2 +10,2 @@
+-func Bar() int { return 1 }
++func Bar() int { return 2 }
+`
+
+func TestParseDiffSplitsFiles(t *testing.T) {
+	files := ParseDiff(twoFileDiff)
+	if len(files) != 2 {
+		t.Fatalf("got %d files, want 2", len(files))
+	}
+	if files[0].Path != "foo.go" {
+		t.Errorf("got path %q, want %q", files[0].Path, "foo.go")
+	}
+	if files[1].Path != "bar.go" {
+		t.Errorf("got path %q, want %q", files[1].Path, "bar.go")
+	}
+}
+
+func TestParseDiffHunkStartLine(t *testing.T) {
+	files := ParseDiff(twoFileDiff)
+
+	if len(files[0].Hunks) != 1 {
+		t.Fatalf("foo.go: got %d hunks, want 1", len(files[0].Hunks))
+	}
+	if got := files[0].Hunks[0].StartLine; got != 1 {
+		t.Errorf("foo.go hunk start line = %d, want 1", got)
+	}
+	if got := files[1].Hunks[0].StartLine; got != 10 {
+		t.Errorf("bar.go hunk start line = %d, want 10", got)
+	}
+}
+
+func TestParseDiffHunkBodyIncludesHeaderAndLines(t *testing.T) {
+	files := ParseDiff(twoFileDiff)
+	body := files[0].Hunks[0].Body
+
+	want := "@@ -1,2 +1,3 @@\n package foo\n+\n func Foo() {}\n"
+	if body != want {
+		t.Errorf("got body %q, want %q", body, want)
+	}
+}
+
+func TestParseDiffMalformedHunkHeaderDefaultsToZero(t *testing.T) {
+	files := ParseDiff("diff --git a/x b/x\n@@ garbage @@\n context\n")
+	if len(files) != 1 || len(files[0].Hunks) != 1 {
+		t.Fatalf("expected one file with one hunk, got %+v", files)
+	}
+	if got := files[0].Hunks[0].StartLine; got != 0 {
+		t.Errorf("got start line %d, want 0 for an unparseable header", got)
+	}
+}
+
+func TestEstimateTokensEmptyString(t *testing.T) {
+	if got := EstimateTokens(""); got != 0 {
+		t.Errorf("got %d, want 0", got)
+	}
+}
+
+func TestEstimateTokensRoundsUpToOne(t *testing.T) {
+	if got := EstimateTokens("ab"); got != 1 {
+		t.Errorf("got %d, want 1 for a string shorter than bytesPerToken", got)
+	}
+}
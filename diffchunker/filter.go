@@ -0,0 +1,26 @@
+package diffchunker
+
+import "strings"
+
+// FilterIgnored drops files whose path starts with any of ignoredPaths
+// (e.g. vendored directories, generated code) before batching.
+func FilterIgnored(files []FileDiff, ignoredPaths []string) []FileDiff {
+	if len(ignoredPaths) == 0 {
+		return files
+	}
+
+	filtered := make([]FileDiff, 0, len(files))
+	for _, f := range files {
+		ignored := false
+		for _, prefix := range ignoredPaths {
+			if strings.HasPrefix(f.Path, prefix) {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
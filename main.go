@@ -4,28 +4,43 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/joho/godotenv"
-	openai "github.com/sashabaranov/go-openai"
+	"github.com/kowalski-ai/api/config"
+	"github.com/kowalski-ai/api/diffchunker"
+	"github.com/kowalski-ai/api/providers"
+	"github.com/kowalski-ai/api/review"
+	"github.com/kowalski-ai/api/webhook"
 	"io"
 	"log"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 )
 
-type Config struct {
-	APIKey      string
-	OpenAIKey   string
-	GithubToken string
-	Port        string
-}
+// healthCheckInterval is how often the background health prober calls
+// every registered provider's HealthCheck.
+const healthCheckInterval = 30 * time.Second
 
+// PRAnalysisRequest describes an analysis request. ModelType is optional:
+// when it is empty, the router picks a provider based on its configured
+// strategy instead of the caller pinning one.
 type PRAnalysisRequest struct {
 	Owner     string `json:"owner"`
 	Repo      string `json:"repo"`
 	PRNumber  int    `json:"pr_number"`
-	ModelType string `json:"model_type"` // "openai" or "claude"
+	ModelType string `json:"model_type,omitempty"` // "openai", "claude", "cohere", "azure_openai", "local"
+	Stream    bool   `json:"stream,omitempty"`
+
+	// MaxChunkTokens caps the estimated token size of each map-reduce
+	// batch; defaults to defaultMaxChunkTokens when zero.
+	MaxChunkTokens int `json:"max_chunk_tokens,omitempty"`
+	// Parallelism bounds how many batches are analyzed concurrently;
+	// defaults to defaultParallelism when zero.
+	Parallelism int `json:"parallelism,omitempty"`
+	// ReduceModel is the provider used to merge per-batch reviews into
+	// the final report; defaults to ModelType when empty. Only the
+	// plain-text path uses it: with ?format=json the reduce pass runs
+	// on ModelType (or the policy's preferred model).
+	ReduceModel string `json:"reduce_model,omitempty"`
 }
 
 type PRAnalysisResponse struct {
@@ -34,29 +49,60 @@ type PRAnalysisResponse struct {
 	ModelUsed string    `json:"model_used"`
 }
 
-func loadConfig() (*Config, error) {
-	if err := godotenv.Load(); err != nil {
-		return nil, fmt.Errorf("error loading .env file: %v", err)
+// StructuredPRAnalysisResponse is returned by /analyze-pr when
+// ?format=json is requested, in place of the free-form Analysis string.
+type StructuredPRAnalysisResponse struct {
+	Review    review.Review `json:"review"`
+	Timestamp time.Time     `json:"timestamp"`
+	ModelUsed string        `json:"model_used"`
+}
+
+// buildRouter registers every provider for which cfg has credentials and
+// returns a Router using cfg.RouterStrategy (defaulting to priority).
+// Under priority, the registration order below (openai, claude, cohere,
+// azure, local) doubles as the priority/failover order; round_robin
+// rotates the starting point through it, and weighted draws the order
+// at random by the weights given here.
+func buildRouter(cfg config.Config) *providers.Router {
+	router := providers.NewRouter(routerStrategy(cfg.RouterStrategy))
+
+	if cfg.OpenAIKey != "" {
+		router.Register(providers.NewOpenAIProvider(cfg.OpenAIKey, ""), 3)
+	}
+	if cfg.ClaudeKey != "" {
+		router.Register(providers.NewClaudeProvider(cfg.ClaudeKey), 2)
+	}
+	if cfg.CohereKey != "" {
+		router.Register(providers.NewCohereProvider(cfg.CohereKey), 1)
 	}
+	if cfg.AzureKey != "" {
+		router.Register(providers.NewAzureOpenAIProvider(cfg.AzureKey, cfg.AzureEndpoint, ""), 1)
+	}
+	if cfg.LocalModelURL != "" {
+		router.Register(providers.NewLocalProvider(cfg.LocalModelURL, ""), 1)
+	}
+
+	return router
+}
 
-	return &Config{
-		APIKey:      os.Getenv("API_KEY"),
-		OpenAIKey:   os.Getenv("OPENAI_API_KEY"),
-		GithubToken: os.Getenv("GITHUB_TOKEN"),
-		Port:        os.Getenv("PORT"),
-	}, nil
+// routerStrategy maps the ROUTER_STRATEGY config value to a
+// providers.Strategy, defaulting to priority for an empty or
+// unrecognized value.
+func routerStrategy(s string) providers.Strategy {
+	switch providers.Strategy(s) {
+	case providers.StrategyRoundRobin:
+		return providers.StrategyRoundRobin
+	case providers.StrategyWeighted:
+		return providers.StrategyWeighted
+	default:
+		return providers.StrategyPriority
+	}
 }
 
 func validateAPIKey(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		apiKey := r.Header.Get("X-API-Key")
-		config, err := loadConfig()
-		if err != nil {
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-			return
-		}
-
-		if apiKey != config.APIKey {
+		if apiKey != cfgManager.Config().APIKey {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
@@ -65,6 +111,15 @@ func validateAPIKey(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// cfgManager holds config and per-repo policy, loaded once at startup
+// and reloadable via SIGHUP/fsnotify instead of re-parsing .env on
+// every request.
+var cfgManager *config.Manager
+
+// router is the process-wide provider router, built once at startup
+// from whichever provider credentials cfgManager has.
+var router *providers.Router
+
 func analyzePRHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -84,31 +139,198 @@ func analyzePRHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Analyze changes using specified model
-	analysis, err := analyzeChanges(changes, req.ModelType)
+	policy := cfgManager.PolicyFor(req.Owner, req.Repo)
+	if policy.MaxDiffSize > 0 && len(changes) > policy.MaxDiffSize {
+		http.Error(w, fmt.Sprintf("Diff size %d exceeds policy max of %d bytes for %s/%s", len(changes), policy.MaxDiffSize, req.Owner, req.Repo), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// format=json returns a structured review.Review via function-calling
+	// / tool-use instead of the legacy free-form Analysis string, so
+	// existing callers of /analyze-pr are unaffected by default.
+	if r.URL.Query().Get("format") == "json" {
+		modelType := req.ModelType
+		if modelType == "" {
+			modelType = policy.PreferredModel
+		}
+		result, err := chunkedStructuredAnalysis(r.Context(), router, changes, modelType, req.MaxChunkTokens, req.Parallelism, policy, nil)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Error analyzing changes: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		response := StructuredPRAnalysisResponse{
+			Review:    result.Review,
+			Timestamp: time.Now(),
+			ModelUsed: result.Provider,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// Chunk, analyze, and (for large PRs) reduce, applying the effective
+	// policy for owner/repo (preferred model, custom prompt, ignored
+	// paths). ModelType, if set on the request, overrides the policy's
+	// preferred model.
+	result, err := chunkedAnalysis(r.Context(), router, changes, req, policy, nil)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error analyzing changes: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	response := PRAnalysisResponse{
-		Analysis:  analysis,
+		Analysis:  result.Analysis,
 		Timestamp: time.Now(),
-		ModelUsed: req.ModelType,
+		ModelUsed: result.Provider,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
-func fetchPRChanges(owner, repo string, prNumber int) (string, error) {
-	log.Printf("Fetching PR changes for %s/%s #%d", owner, repo, prNumber)
-	config, err := loadConfig()
+// sseDeltaEvent is the payload of each `event: message` frame emitted by
+// analyzePRStreamHandler.
+type sseDeltaEvent struct {
+	Delta    string `json:"delta"`
+	Provider string `json:"provider"`
+}
+
+// sseDoneEvent is the payload of the final `event: done` frame, carrying
+// aggregated metadata once streaming completes.
+type sseDoneEvent struct {
+	ModelUsed   string `json:"model_used"`
+	TotalTokens int    `json:"total_tokens"`
+	ElapsedMs   int64  `json:"elapsed_ms"`
+}
+
+// sseProgressEvent is the payload of each `event: progress` frame
+// emitted while a large PR is being analyzed in map-reduce batches.
+type sseProgressEvent struct {
+	Status string `json:"status"`
+}
+
+// sseErrorEvent is the payload of an `event: error` frame. Marshaling
+// it (rather than writing err.Error() raw) keeps a wrapped error's
+// embedded newlines from breaking SSE's blank-line frame delimiter.
+type sseErrorEvent struct {
+	Error string `json:"error"`
+}
+
+func writeSSEError(w http.ResponseWriter, flusher http.Flusher, err error) {
+	payload, _ := json.Marshal(sseErrorEvent{Error: err.Error()})
+	fmt.Fprintf(w, "event: error\ndata: %s\n\n", payload)
+	flusher.Flush()
+}
+
+func analyzePRStreamHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req PRAnalysisRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	changes, err := fetchPRChanges(req.Owner, req.Repo, req.PRNumber)
 	if err != nil {
-		log.Printf("Config loading failed: %v", err)
-		return "", err
+		http.Error(w, fmt.Sprintf("Error fetching PR changes: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	policy := cfgManager.PolicyFor(req.Owner, req.Repo)
+	if policy.MaxDiffSize > 0 && len(changes) > policy.MaxDiffSize {
+		http.Error(w, fmt.Sprintf("Diff size %d exceeds policy max of %d bytes for %s/%s", len(changes), policy.MaxDiffSize, req.Owner, req.Repo), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
 	}
 
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	start := time.Now()
+
+	maxChunkTokens := req.MaxChunkTokens
+	if maxChunkTokens <= 0 {
+		maxChunkTokens = defaultMaxChunkTokens
+	}
+	batches := diffchunker.PackBatches(diffchunker.FilterIgnored(diffchunker.ParseDiff(changes), policy.IgnoredPaths), maxChunkTokens)
+	if len(batches) == 0 {
+		writeSSEError(w, flusher, fmt.Errorf("diff produced no reviewable content"))
+		return
+	}
+	if policy.CustomPrompt != "" {
+		for i := range batches {
+			batches[i].Content = policy.CustomPrompt + "\n\n" + batches[i].Content
+		}
+	}
+
+	var provider string
+	var totalTokens int
+	if len(batches) > 1 {
+		// Large PR: stream map-reduce progress instead of per-token
+		// deltas, since there's no single upstream token stream to
+		// relay while batches are being analyzed concurrently.
+		result, err := chunkedAnalysis(ctx, router, changes, req, policy, func(status string) {
+			payload, _ := json.Marshal(sseProgressEvent{Status: status})
+			fmt.Fprintf(w, "event: progress\ndata: %s\n\n", payload)
+			flusher.Flush()
+		})
+		if err != nil {
+			writeSSEError(w, flusher, err)
+			return
+		}
+		payload, _ := json.Marshal(sseDeltaEvent{Delta: result.Analysis, Provider: result.Provider})
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", payload)
+		flusher.Flush()
+		provider = result.Provider
+	} else {
+		modelType := req.ModelType
+		if modelType == "" {
+			modelType = policy.PreferredModel
+		}
+		result, err := router.AnalyzeStream(ctx, batches[0].Content, modelType, func(provider, delta string) {
+			if delta == "" {
+				return
+			}
+			payload, _ := json.Marshal(sseDeltaEvent{Delta: delta, Provider: provider})
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", payload)
+			flusher.Flush()
+		})
+		if err != nil {
+			writeSSEError(w, flusher, err)
+			return
+		}
+		provider = result.Provider
+		totalTokens = result.Usage.InputTokens + result.Usage.OutputTokens
+	}
+
+	done := sseDoneEvent{
+		ModelUsed:   provider,
+		TotalTokens: totalTokens,
+		ElapsedMs:   time.Since(start).Milliseconds(),
+	}
+	payload, _ := json.Marshal(done)
+	fmt.Fprintf(w, "event: done\ndata: %s\n\n", payload)
+	flusher.Flush()
+}
+
+func fetchPRChanges(owner, repo string, prNumber int) (string, error) {
+	log.Printf("Fetching PR changes for %s/%s #%d", owner, repo, prNumber)
+
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", owner, repo, prNumber)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -116,7 +338,7 @@ func fetchPRChanges(owner, repo string, prNumber int) (string, error) {
 		return "", err
 	}
 
-	req.Header.Set("Authorization", "token "+config.GithubToken)
+	req.Header.Set("Authorization", "token "+cfgManager.Config().GithubToken)
 	req.Header.Set("Accept", "application/vnd.github.v3.diff")
 
 	client := &http.Client{}
@@ -154,53 +376,44 @@ func fetchPRChanges(owner, repo string, prNumber int) (string, error) {
 	return diffBuilder.String(), nil
 }
 
-func analyzeChanges(changes, modelType string) (string, error) {
-	config, err := loadConfig()
-	if err != nil {
-		return "", err
+// adminConfigHandler dumps the effective policy for ?owner=&repo=,
+// i.e. the policy file's default merged with that repo's override.
+func adminConfigHandler(w http.ResponseWriter, r *http.Request) {
+	owner := r.URL.Query().Get("owner")
+	repo := r.URL.Query().Get("repo")
+	if owner == "" || repo == "" {
+		http.Error(w, "owner and repo query parameters are required", http.StatusBadRequest)
+		return
 	}
 
-	switch modelType {
-	case "openai":
-		client := openai.NewClient(config.OpenAIKey)
-		resp, err := client.CreateChatCompletion(
-			context.Background(),
-			openai.ChatCompletionRequest{
-				Model: openai.GPT4,
-				Messages: []openai.ChatCompletionMessage{
-					{
-						Role: openai.ChatMessageRoleUser,
-						Content: fmt.Sprintf(
-							"Please analyze the following code changes and provide a detailed review:\n\n%s",
-							changes,
-						),
-					},
-				},
-			},
-		)
-		if err != nil {
-			return "", err
-		}
-		return resp.Choices[0].Message.Content, nil
-
-	case "claude":
-		// Implement Claude API integration here
-		return "", fmt.Errorf("Claude integration not implemented yet")
-
-	default:
-		return "", fmt.Errorf("unsupported model type: %s", modelType)
-	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cfgManager.PolicyFor(owner, repo))
 }
 
 func main() {
-	config, err := loadConfig()
+	var err error
+	cfgManager, err = config.NewManager()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
+	cfgManager.Watch()
+	defer cfgManager.Stop()
+
+	router = buildRouter(cfgManager.Config())
+
+	healthCheckCtx, stopHealthChecks := context.WithCancel(context.Background())
+	defer stopHealthChecks()
+	router.StartHealthChecks(healthCheckCtx, healthCheckInterval)
+
+	webhookQueue = webhook.NewQueue(3, 64)
+	defer webhookQueue.Stop()
 
 	http.HandleFunc("/analyze-pr", validateAPIKey(analyzePRHandler))
+	http.HandleFunc("/analyze-pr/stream", validateAPIKey(analyzePRStreamHandler))
+	http.HandleFunc("/admin/config", validateAPIKey(adminConfigHandler))
+	http.HandleFunc("/webhook/github", webhookHandler)
 
-	port := config.Port
+	port := cfgManager.Config().Port
 	if port == "" {
 		port = "8080"
 	}
@@ -0,0 +1,62 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// AzureOpenAIProvider analyzes diffs using an Azure-hosted OpenAI
+// deployment.
+type AzureOpenAIProvider struct {
+	client     *openai.Client
+	deployment string
+}
+
+// NewAzureOpenAIProvider builds an AzureOpenAIProvider. endpoint is the
+// Azure resource endpoint (e.g. https://my-resource.openai.azure.com/)
+// and deployment is the model deployment name.
+func NewAzureOpenAIProvider(apiKey, endpoint, deployment string) *AzureOpenAIProvider {
+	config := openai.DefaultAzureConfig(apiKey, endpoint)
+	config.AzureModelMapperFunc = func(model string) string { return deployment }
+	return &AzureOpenAIProvider{
+		client:     openai.NewClientWithConfig(config),
+		deployment: deployment,
+	}
+}
+
+// Name implements Provider.
+func (p *AzureOpenAIProvider) Name() string { return "azure_openai" }
+
+// Analyze implements Provider.
+func (p *AzureOpenAIProvider) Analyze(ctx context.Context, diff string) (string, error) {
+	resp, err := p.client.CreateChatCompletion(
+		ctx,
+		openai.ChatCompletionRequest{
+			Model: p.deployment,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role: openai.ChatMessageRoleUser,
+					Content: fmt.Sprintf(
+						"Please analyze the following code changes and provide a detailed review:\n\n%s",
+						diff,
+					),
+				},
+			},
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("azure_openai: empty response")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// HealthCheck implements Provider.
+func (p *AzureOpenAIProvider) HealthCheck(ctx context.Context) error {
+	_, err := p.client.ListModels(ctx)
+	return err
+}
@@ -0,0 +1,298 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kowalski-ai/api/review"
+)
+
+const (
+	anthropicAPIURL    = "https://api.anthropic.com/v1/messages"
+	anthropicVersion   = "2023-06-01"
+	claudeDefaultModel = "claude-3-5-sonnet-latest"
+	claudeMaxTokens    = 2048
+	claudeSystemPrompt = "You are an expert code reviewer. Analyze the following code changes and provide a detailed, actionable review."
+)
+
+// ClaudeProvider analyzes diffs using Anthropic's Messages API.
+type ClaudeProvider struct {
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+// NewClaudeProvider builds a ClaudeProvider from an Anthropic API key.
+func NewClaudeProvider(apiKey string) *ClaudeProvider {
+	return &ClaudeProvider{apiKey: apiKey, model: claudeDefaultModel, client: &http.Client{}}
+}
+
+// Name implements Provider.
+func (p *ClaudeProvider) Name() string { return "claude" }
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+type anthropicRequest struct {
+	Model      string               `json:"model"`
+	MaxTokens  int                  `json:"max_tokens"`
+	System     string               `json:"system"`
+	Messages   []anthropicMessage   `json:"messages"`
+	Stream     bool                 `json:"stream,omitempty"`
+	Tools      []anthropicTool      `json:"tools,omitempty"`
+	ToolChoice *anthropicToolChoice `json:"tool_choice,omitempty"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type anthropicContentBlock struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
+}
+
+type anthropicResponse struct {
+	Content []anthropicContentBlock `json:"content"`
+	Usage   anthropicUsage          `json:"usage"`
+}
+
+func (p *ClaudeProvider) newRequest(ctx context.Context, body anthropicRequest) (*http.Request, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, anthropicAPIURL, bytes.NewReader(encoded))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+	req.Header.Set("content-type", "application/json")
+	return req, nil
+}
+
+// Analyze implements Provider.
+func (p *ClaudeProvider) Analyze(ctx context.Context, diff string) (string, error) {
+	req, err := p.newRequest(ctx, anthropicRequest{
+		Model:     p.model,
+		MaxTokens: claudeMaxTokens,
+		System:    claudeSystemPrompt,
+		Messages:  []anthropicMessage{{Role: "user", Content: diff}},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	parsed, err := decodeAnthropicResponse(resp)
+	if err != nil {
+		return "", err
+	}
+	if len(parsed.Content) == 0 {
+		return "", fmt.Errorf("claude: empty response")
+	}
+	return parsed.Content[0].Text, nil
+}
+
+// AnalyzeStructured implements StructuredProvider using Claude's tool_use
+// mechanism: the review schema is registered as the only tool and
+// tool_choice forces the model to call it, retrying once with a repair
+// prompt if the returned input fails review.Validate.
+func (p *ClaudeProvider) AnalyzeStructured(ctx context.Context, diff string) (review.Review, error) {
+	schema, err := review.AnthropicInputSchema()
+	if err != nil {
+		return review.Review{}, err
+	}
+
+	tool := anthropicTool{
+		Name:        review.Name,
+		Description: review.Description,
+		InputSchema: schema,
+	}
+
+	content := diff
+	var lastErr error
+	for attempt := 0; attempt <= maxRepairAttempts; attempt++ {
+		if attempt > 0 {
+			content = repairPrompt(content, lastErr)
+		}
+
+		req, err := p.newRequest(ctx, anthropicRequest{
+			Model:      p.model,
+			MaxTokens:  claudeMaxTokens,
+			System:     claudeSystemPrompt,
+			Messages:   []anthropicMessage{{Role: "user", Content: content}},
+			Tools:      []anthropicTool{tool},
+			ToolChoice: &anthropicToolChoice{Type: "tool", Name: review.Name},
+		})
+		if err != nil {
+			return review.Review{}, err
+		}
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return review.Review{}, err
+		}
+		parsed, err := decodeAnthropicResponse(resp)
+		if err != nil {
+			return review.Review{}, err
+		}
+
+		var toolInput json.RawMessage
+		for _, block := range parsed.Content {
+			if block.Type == "tool_use" && block.Name == review.Name {
+				toolInput = block.Input
+				break
+			}
+		}
+		if toolInput == nil {
+			return review.Review{}, fmt.Errorf("claude: no %s tool_use block in response", review.Name)
+		}
+
+		result, err := review.Parse(toolInput)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if err := review.Validate(result); err != nil {
+			lastErr = err
+			continue
+		}
+		return result, nil
+	}
+
+	return review.Review{}, fmt.Errorf("claude: response failed validation after retry: %w", lastErr)
+}
+
+func decodeAnthropicResponse(resp *http.Response) (anthropicResponse, error) {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return anthropicResponse{}, fmt.Errorf("claude: api returned status %d", resp.StatusCode)
+	}
+	var parsed anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return anthropicResponse{}, err
+	}
+	return parsed, nil
+}
+
+// HealthCheck implements Provider.
+func (p *ClaudeProvider) HealthCheck(ctx context.Context) error {
+	if p.apiKey == "" {
+		return fmt.Errorf("claude: missing API key")
+	}
+	return nil
+}
+
+// contentBlockDelta is the payload of a `content_block_delta` SSE event.
+type contentBlockDelta struct {
+	Delta struct {
+		Text string `json:"text"`
+	} `json:"delta"`
+}
+
+// messageStart is the payload of a `message_start` SSE event, carrying
+// the prompt's input token count.
+type messageStart struct {
+	Message struct {
+		Usage struct {
+			InputTokens int `json:"input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// messageDelta is the payload of a `message_delta` SSE event, carrying
+// cumulative output token usage.
+type messageDelta struct {
+	Usage struct {
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// AnalyzeStream implements StreamingProvider by consuming Anthropic's SSE
+// stream, accumulating content_block_delta text into onDelta calls.
+func (p *ClaudeProvider) AnalyzeStream(ctx context.Context, diff string, onDelta func(delta string)) (Usage, error) {
+	req, err := p.newRequest(ctx, anthropicRequest{
+		Model:     p.model,
+		MaxTokens: claudeMaxTokens,
+		System:    claudeSystemPrompt,
+		Messages:  []anthropicMessage{{Role: "user", Content: diff}},
+		Stream:    true,
+	})
+	if err != nil {
+		return Usage{}, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Usage{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Usage{}, fmt.Errorf("claude: api returned status %d", resp.StatusCode)
+	}
+
+	var usage Usage
+	scanner := bufio.NewScanner(resp.Body)
+	var event string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			switch event {
+			case "message_start":
+				var start messageStart
+				if err := json.Unmarshal([]byte(data), &start); err != nil {
+					continue
+				}
+				usage.InputTokens = start.Message.Usage.InputTokens
+			case "content_block_delta":
+				var delta contentBlockDelta
+				if err := json.Unmarshal([]byte(data), &delta); err != nil {
+					continue
+				}
+				onDelta(delta.Delta.Text)
+			case "message_delta":
+				var md messageDelta
+				if err := json.Unmarshal([]byte(data), &md); err != nil {
+					continue
+				}
+				usage.OutputTokens = md.Usage.OutputTokens
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return usage, err
+	}
+	return usage, nil
+}
@@ -0,0 +1,36 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+)
+
+// CohereProvider analyzes diffs using Cohere's chat API.
+//
+// TODO: wire up the real Cohere chat endpoint; this is currently a stub
+// so the provider can be registered with the Router ahead of the full
+// integration.
+type CohereProvider struct {
+	apiKey string
+}
+
+// NewCohereProvider builds a CohereProvider from a Cohere API key.
+func NewCohereProvider(apiKey string) *CohereProvider {
+	return &CohereProvider{apiKey: apiKey}
+}
+
+// Name implements Provider.
+func (p *CohereProvider) Name() string { return "cohere" }
+
+// Analyze implements Provider.
+func (p *CohereProvider) Analyze(ctx context.Context, diff string) (string, error) {
+	return "", fmt.Errorf("cohere: integration not implemented yet")
+}
+
+// HealthCheck implements Provider.
+func (p *CohereProvider) HealthCheck(ctx context.Context) error {
+	if p.apiKey == "" {
+		return fmt.Errorf("cohere: missing API key")
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package providers
+
+import (
+	"sync"
+	"time"
+)
+
+// healthTracker records consecutive failures per provider and takes a
+// provider out of rotation for a cooldown window once it trips the
+// failure threshold, similar to how Glide manages its provider pools.
+type healthTracker struct {
+	mu                  sync.Mutex
+	failureThreshold    int
+	cooldown            time.Duration
+	consecutiveFailures map[string]int
+	unhealthyUntil      map[string]time.Time
+}
+
+func newHealthTracker(failureThreshold int, cooldown time.Duration) *healthTracker {
+	return &healthTracker{
+		failureThreshold:    failureThreshold,
+		cooldown:            cooldown,
+		consecutiveFailures: make(map[string]int),
+		unhealthyUntil:      make(map[string]time.Time),
+	}
+}
+
+// recordSuccess clears a provider's failure streak and any cooldown.
+func (h *healthTracker) recordSuccess(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.consecutiveFailures[name] = 0
+	delete(h.unhealthyUntil, name)
+}
+
+// recordFailure bumps a provider's failure streak, putting it into
+// cooldown once the threshold is reached.
+func (h *healthTracker) recordFailure(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.consecutiveFailures[name]++
+	if h.consecutiveFailures[name] >= h.failureThreshold {
+		h.unhealthyUntil[name] = time.Now().Add(h.cooldown)
+	}
+}
+
+// isHealthy reports whether name is currently eligible for selection.
+func (h *healthTracker) isHealthy(name string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	until, cooling := h.unhealthyUntil[name]
+	if !cooling {
+		return true
+	}
+	if time.Now().After(until) {
+		return true
+	}
+	return false
+}
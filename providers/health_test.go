@@ -0,0 +1,59 @@
+package providers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHealthTrackerHealthyBeforeThreshold(t *testing.T) {
+	h := newHealthTracker(3, time.Minute)
+
+	h.recordFailure("p")
+	h.recordFailure("p")
+	if !h.isHealthy("p") {
+		t.Fatal("expected provider to still be healthy below the failure threshold")
+	}
+}
+
+func TestHealthTrackerUnhealthyAtThreshold(t *testing.T) {
+	h := newHealthTracker(3, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		h.recordFailure("p")
+	}
+	if h.isHealthy("p") {
+		t.Fatal("expected provider to be unhealthy once it hits the failure threshold")
+	}
+}
+
+func TestHealthTrackerRecoversAfterCooldown(t *testing.T) {
+	h := newHealthTracker(1, -time.Minute) // cooldown already elapsed
+
+	h.recordFailure("p")
+	if !h.isHealthy("p") {
+		t.Fatal("expected provider to be healthy again once its cooldown window has passed")
+	}
+}
+
+func TestHealthTrackerSuccessResetsStreak(t *testing.T) {
+	h := newHealthTracker(3, time.Minute)
+
+	h.recordFailure("p")
+	h.recordFailure("p")
+	h.recordSuccess("p")
+
+	if h.consecutiveFailures["p"] != 0 {
+		t.Fatalf("expected recordSuccess to reset the failure streak, got %d", h.consecutiveFailures["p"])
+	}
+	if !h.isHealthy("p") {
+		t.Fatal("expected provider to be healthy after a success")
+	}
+}
+
+func TestHealthTrackerUnknownProviderIsHealthy(t *testing.T) {
+	h := newHealthTracker(3, time.Minute)
+
+	if !h.isHealthy("never-seen") {
+		t.Fatal("expected a provider with no recorded history to be considered healthy")
+	}
+}
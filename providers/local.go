@@ -0,0 +1,104 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// LocalProvider analyzes diffs using a locally hosted model that speaks
+// an OpenAI-compatible chat completions API (e.g. Ollama, vLLM, LM
+// Studio).
+type LocalProvider struct {
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+// NewLocalProvider builds a LocalProvider targeting baseURL (e.g.
+// http://localhost:11434) and model.
+func NewLocalProvider(baseURL, model string) *LocalProvider {
+	return &LocalProvider{baseURL: baseURL, model: model, client: &http.Client{}}
+}
+
+type localChatRequest struct {
+	Model    string             `json:"model"`
+	Messages []localChatMessage `json:"messages"`
+}
+
+type localChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type localChatResponse struct {
+	Choices []struct {
+		Message localChatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// Name implements Provider.
+func (p *LocalProvider) Name() string { return "local" }
+
+// Analyze implements Provider.
+func (p *LocalProvider) Analyze(ctx context.Context, diff string) (string, error) {
+	body, err := json.Marshal(localChatRequest{
+		Model: p.model,
+		Messages: []localChatMessage{
+			{
+				Role: "user",
+				Content: fmt.Sprintf(
+					"Please analyze the following code changes and provide a detailed review:\n\n%s",
+					diff,
+				),
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v1/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("local: model server returned status %d", resp.StatusCode)
+	}
+
+	var parsed localChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", err
+	}
+	if len(parsed.Choices) == 0 {
+		return "", fmt.Errorf("local: empty response")
+	}
+	return parsed.Choices[0].Message.Content, nil
+}
+
+// HealthCheck implements Provider.
+func (p *LocalProvider) HealthCheck(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/v1/models", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("local: health check returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	openai "github.com/sashabaranov/go-openai"
+
+	"github.com/kowalski-ai/api/review"
+)
+
+// OpenAIProvider analyzes diffs using the OpenAI chat completion API.
+type OpenAIProvider struct {
+	client *openai.Client
+	model  string
+}
+
+// NewOpenAIProvider builds an OpenAIProvider from an API key. If model is
+// empty, openai.GPT4 is used.
+func NewOpenAIProvider(apiKey, model string) *OpenAIProvider {
+	if model == "" {
+		model = openai.GPT4
+	}
+	return &OpenAIProvider{client: openai.NewClient(apiKey), model: model}
+}
+
+// Name implements Provider.
+func (p *OpenAIProvider) Name() string { return "openai" }
+
+// Analyze implements Provider.
+func (p *OpenAIProvider) Analyze(ctx context.Context, diff string) (string, error) {
+	resp, err := p.client.CreateChatCompletion(
+		ctx,
+		openai.ChatCompletionRequest{
+			Model: p.model,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role: openai.ChatMessageRoleUser,
+					Content: fmt.Sprintf(
+						"Please analyze the following code changes and provide a detailed review:\n\n%s",
+						diff,
+					),
+				},
+			},
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("openai: empty response")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// HealthCheck implements Provider.
+func (p *OpenAIProvider) HealthCheck(ctx context.Context) error {
+	_, err := p.client.ListModels(ctx)
+	return err
+}
+
+// AnalyzeStream implements StreamingProvider, relaying token deltas from
+// go-openai's chat completion stream as they arrive.
+func (p *OpenAIProvider) AnalyzeStream(ctx context.Context, diff string, onDelta func(delta string)) (Usage, error) {
+	stream, err := p.client.CreateChatCompletionStream(
+		ctx,
+		openai.ChatCompletionRequest{
+			Model: p.model,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role: openai.ChatMessageRoleUser,
+					Content: fmt.Sprintf(
+						"Please analyze the following code changes and provide a detailed review:\n\n%s",
+						diff,
+					),
+				},
+			},
+			StreamOptions: &openai.StreamOptions{IncludeUsage: true},
+		},
+	)
+	if err != nil {
+		return Usage{}, err
+	}
+	defer stream.Close()
+
+	var usage Usage
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return usage, err
+		}
+		if chunk.Usage != nil {
+			usage.InputTokens = chunk.Usage.PromptTokens
+			usage.OutputTokens = chunk.Usage.CompletionTokens
+		}
+		if len(chunk.Choices) > 0 {
+			onDelta(chunk.Choices[0].Delta.Content)
+		}
+	}
+	return usage, nil
+}
+
+// AnalyzeStructured implements StructuredProvider using response_format
+// json_schema, retrying once with a repair prompt if the model's JSON
+// fails review.Validate.
+func (p *OpenAIProvider) AnalyzeStructured(ctx context.Context, diff string) (review.Review, error) {
+	prompt := fmt.Sprintf(
+		"Please analyze the following code changes and provide a detailed review:\n\n%s",
+		diff,
+	)
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRepairAttempts; attempt++ {
+		if attempt > 0 {
+			prompt = repairPrompt(prompt, lastErr)
+		}
+
+		resp, err := p.client.CreateChatCompletion(
+			ctx,
+			openai.ChatCompletionRequest{
+				Model: p.model,
+				Messages: []openai.ChatCompletionMessage{
+					{Role: openai.ChatMessageRoleUser, Content: prompt},
+				},
+				ResponseFormat: &openai.ChatCompletionResponseFormat{
+					Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+					JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+						Name:   review.Name,
+						Schema: review.Definition(),
+						Strict: true,
+					},
+				},
+			},
+		)
+		if err != nil {
+			return review.Review{}, err
+		}
+		if len(resp.Choices) == 0 {
+			return review.Review{}, fmt.Errorf("openai: empty response")
+		}
+
+		parsed, err := review.Parse([]byte(resp.Choices[0].Message.Content))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if err := review.Validate(parsed); err != nil {
+			lastErr = err
+			continue
+		}
+		return parsed, nil
+	}
+
+	return review.Review{}, fmt.Errorf("openai: response failed validation after retry: %w", lastErr)
+}
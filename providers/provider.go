@@ -0,0 +1,41 @@
+// Package providers implements a pluggable subsystem for LLM-backed code
+// review providers (OpenAI, Anthropic Claude, Cohere, Azure OpenAI, local
+// models) and a Router that fans out requests across them with
+// health-based failover.
+package providers
+
+import "context"
+
+// Provider is implemented by anything that can turn a diff into a code
+// review and report its own health.
+type Provider interface {
+	// Name returns the provider's identifier, e.g. "openai" or "claude".
+	Name() string
+
+	// Analyze sends diff to the underlying model and returns its review.
+	Analyze(ctx context.Context, diff string) (string, error)
+
+	// HealthCheck reports whether the provider is currently reachable and
+	// able to serve requests.
+	HealthCheck(ctx context.Context) error
+}
+
+// Usage reports token accounting for a completed (possibly streamed)
+// analysis call.
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+// StreamingProvider is implemented by providers that can emit their
+// review incrementally instead of waiting for the full completion.
+// Providers that don't support streaming simply don't implement it;
+// Router.AnalyzeStream skips those.
+type StreamingProvider interface {
+	Provider
+
+	// AnalyzeStream sends diff to the underlying model and invokes
+	// onDelta once per token chunk as it arrives. It returns usage
+	// accounting for the completed call.
+	AnalyzeStream(ctx context.Context, diff string, onDelta func(delta string)) (Usage, error)
+}
@@ -0,0 +1,335 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kowalski-ai/api/review"
+)
+
+// Strategy selects how the Router picks among its healthy providers.
+type Strategy string
+
+const (
+	// StrategyPriority always prefers the first healthy provider in
+	// registration order.
+	StrategyPriority Strategy = "priority"
+	// StrategyRoundRobin cycles through healthy providers in turn.
+	StrategyRoundRobin Strategy = "round_robin"
+	// StrategyWeighted picks among healthy providers proportionally to
+	// their configured weight.
+	StrategyWeighted Strategy = "weighted"
+)
+
+const (
+	defaultFailureThreshold = 3
+	defaultCooldown         = 30 * time.Second
+)
+
+// entry pairs a registered Provider with its routing weight.
+type entry struct {
+	provider Provider
+	weight   int
+}
+
+// Router fans out Analyze calls across a set of registered providers,
+// applying a selection Strategy and skipping providers the health
+// tracker has marked unhealthy, with automatic failover to the next
+// candidate on error.
+type Router struct {
+	mu       sync.RWMutex
+	entries  []entry
+	strategy Strategy
+	health   *healthTracker
+	rrCursor uint64
+}
+
+// NewRouter creates a Router using the given strategy. Providers are
+// added with Register.
+func NewRouter(strategy Strategy) *Router {
+	return &Router{
+		strategy: strategy,
+		health:   newHealthTracker(defaultFailureThreshold, defaultCooldown),
+	}
+}
+
+// Register adds a provider to the pool with the given weight. weight is
+// only consulted under StrategyWeighted.
+func (r *Router) Register(p Provider, weight int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, entry{provider: p, weight: weight})
+}
+
+// Result describes the outcome of a routed Analyze call.
+type Result struct {
+	Analysis string
+	Provider string
+}
+
+// Analyze picks a provider according to the Router's strategy and asks
+// it to analyze diff, automatically failing over to the next healthy
+// candidate if the chosen provider errors. If preferred is non-empty,
+// that provider is tried first (still subject to failover) regardless
+// of strategy.
+func (r *Router) Analyze(ctx context.Context, diff string, preferred string) (Result, error) {
+	candidates := r.orderedCandidates()
+	if preferred != "" {
+		candidates = promote(candidates, preferred)
+	}
+	if len(candidates) == 0 {
+		return Result{}, fmt.Errorf("providers: no healthy providers registered")
+	}
+
+	var lastErr error
+	for _, p := range candidates {
+		analysis, err := p.Analyze(ctx, diff)
+		if err != nil {
+			r.health.recordFailure(p.Name())
+			lastErr = err
+			continue
+		}
+		r.health.recordSuccess(p.Name())
+		return Result{Analysis: analysis, Provider: p.Name()}, nil
+	}
+
+	return Result{}, fmt.Errorf("providers: all providers failed, last error: %w", lastErr)
+}
+
+// StreamResult describes the outcome of a routed AnalyzeStream call.
+type StreamResult struct {
+	Provider string
+	Usage    Usage
+}
+
+// AnalyzeStream behaves like Analyze but streams token deltas to onDelta
+// as they arrive; onDelta is given the serving provider's name alongside
+// each chunk. Only candidates implementing StreamingProvider are
+// considered; if none do, it returns an error.
+//
+// Failover only happens before the first delta of an attempt is
+// emitted. Once onDelta has been called for a candidate, that
+// candidate's partial output is already written to the caller's
+// stream (e.g. an SSE connection); trying the next candidate at that
+// point would concatenate a second, unrelated response onto it, so a
+// mid-stream error is returned directly instead.
+func (r *Router) AnalyzeStream(ctx context.Context, diff string, preferred string, onDelta func(provider, delta string)) (StreamResult, error) {
+	candidates := r.orderedCandidates()
+	if preferred != "" {
+		candidates = promote(candidates, preferred)
+	}
+
+	var lastErr error
+	tried := false
+	for _, p := range candidates {
+		sp, ok := p.(StreamingProvider)
+		if !ok {
+			continue
+		}
+		tried = true
+		name := p.Name()
+		emitted := false
+		usage, err := sp.AnalyzeStream(ctx, diff, func(delta string) {
+			emitted = true
+			onDelta(name, delta)
+		})
+		if err != nil {
+			r.health.recordFailure(p.Name())
+			if emitted {
+				return StreamResult{}, fmt.Errorf("providers: %s failed mid-stream: %w", name, err)
+			}
+			lastErr = err
+			continue
+		}
+		r.health.recordSuccess(p.Name())
+		return StreamResult{Provider: p.Name(), Usage: usage}, nil
+	}
+
+	if !tried {
+		return StreamResult{}, fmt.Errorf("providers: no healthy streaming-capable providers registered")
+	}
+	return StreamResult{}, fmt.Errorf("providers: all streaming providers failed, last error: %w", lastErr)
+}
+
+// StructuredResult describes the outcome of a routed AnalyzeStructured
+// call.
+type StructuredResult struct {
+	Review   review.Review
+	Provider string
+}
+
+// AnalyzeStructured behaves like Analyze but returns a structured
+// review.Review. Only candidates implementing StructuredProvider are
+// considered; if none do, it returns an error.
+func (r *Router) AnalyzeStructured(ctx context.Context, diff string, preferred string) (StructuredResult, error) {
+	candidates := r.orderedCandidates()
+	if preferred != "" {
+		candidates = promote(candidates, preferred)
+	}
+
+	var lastErr error
+	tried := false
+	for _, p := range candidates {
+		sp, ok := p.(StructuredProvider)
+		if !ok {
+			continue
+		}
+		tried = true
+		result, err := sp.AnalyzeStructured(ctx, diff)
+		if err != nil {
+			r.health.recordFailure(p.Name())
+			lastErr = err
+			continue
+		}
+		r.health.recordSuccess(p.Name())
+		return StructuredResult{Review: result, Provider: p.Name()}, nil
+	}
+
+	if !tried {
+		return StructuredResult{}, fmt.Errorf("providers: no healthy structured-output-capable providers registered")
+	}
+	return StructuredResult{}, fmt.Errorf("providers: all structured providers failed, last error: %w", lastErr)
+}
+
+// StartHealthChecks launches a background goroutine that calls every
+// registered provider's HealthCheck every interval, feeding the result
+// into the same health tracker Analyze/AnalyzeStream/AnalyzeStructured
+// use for failover. This catches a provider that's down before a real
+// request has to hit it and fail first. It runs until ctx is done.
+func (r *Router) StartHealthChecks(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.probeAll(ctx)
+			}
+		}
+	}()
+}
+
+// probeAll runs one round of HealthCheck against every registered
+// provider, recording the outcome in the health tracker.
+func (r *Router) probeAll(ctx context.Context) {
+	r.mu.RLock()
+	entries := make([]entry, len(r.entries))
+	copy(entries, r.entries)
+	r.mu.RUnlock()
+
+	for _, e := range entries {
+		if err := e.provider.HealthCheck(ctx); err != nil {
+			r.health.recordFailure(e.provider.Name())
+			continue
+		}
+		r.health.recordSuccess(e.provider.Name())
+	}
+}
+
+// promote reorders candidates so the provider named preferred (if
+// present) comes first, leaving the relative order of the rest intact
+// for failover.
+func promote(candidates []Provider, preferred string) []Provider {
+	for i, p := range candidates {
+		if p.Name() == preferred {
+			reordered := make([]Provider, 0, len(candidates))
+			reordered = append(reordered, p)
+			reordered = append(reordered, candidates[:i]...)
+			reordered = append(reordered, candidates[i+1:]...)
+			return reordered
+		}
+	}
+	return candidates
+}
+
+// orderedCandidates returns the healthy registered providers ordered
+// according to the Router's strategy.
+func (r *Router) orderedCandidates() []Provider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	healthy := make([]entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		if r.health.isHealthy(e.provider.Name()) {
+			healthy = append(healthy, e)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+
+	switch r.strategy {
+	case StrategyRoundRobin:
+		start := int(atomic.AddUint64(&r.rrCursor, 1)-1) % len(healthy)
+		ordered := make([]Provider, 0, len(healthy))
+		for i := range healthy {
+			ordered = append(ordered, healthy[(start+i)%len(healthy)].provider)
+		}
+		return ordered
+	case StrategyWeighted:
+		return weightedOrder(healthy)
+	case StrategyPriority:
+		fallthrough
+	default:
+		ordered := make([]Provider, 0, len(healthy))
+		for _, e := range healthy {
+			ordered = append(ordered, e.provider)
+		}
+		return ordered
+	}
+}
+
+// weightedOrder draws providers without replacement, each draw picking
+// among the remainder with probability proportional to its weight, so
+// load is distributed across providers roughly in proportion to their
+// configured weight rather than always preferring the heaviest one.
+// The result still gives every provider a position, so it doubles as a
+// failover order if the first pick errors.
+func weightedOrder(healthy []entry) []Provider {
+	remaining := make([]entry, len(healthy))
+	copy(remaining, healthy)
+
+	ordered := make([]Provider, 0, len(remaining))
+	for len(remaining) > 0 {
+		total := 0
+		for _, e := range remaining {
+			total += nonNegative(e.weight)
+		}
+
+		var pick int
+		if total == 0 {
+			// All remaining weights are non-positive: fall back to
+			// uniform selection instead of always picking index 0.
+			pick = rand.Intn(len(remaining))
+		} else {
+			r := rand.Intn(total)
+			cum := 0
+			for i, e := range remaining {
+				cum += nonNegative(e.weight)
+				if r < cum {
+					pick = i
+					break
+				}
+			}
+		}
+
+		ordered = append(ordered, remaining[pick].provider)
+		remaining = append(remaining[:pick], remaining[pick+1:]...)
+	}
+	return ordered
+}
+
+func nonNegative(n int) int {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
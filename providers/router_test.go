@@ -0,0 +1,118 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeProvider is a minimal Provider used to exercise the router
+// without hitting a real LLM API.
+type fakeProvider struct {
+	name string
+	err  error
+}
+
+func (f *fakeProvider) Name() string { return f.name }
+
+func (f *fakeProvider) Analyze(ctx context.Context, diff string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	return "analysis from " + f.name, nil
+}
+
+func (f *fakeProvider) HealthCheck(ctx context.Context) error { return f.err }
+
+func TestWeightedOrderIncludesEveryProvider(t *testing.T) {
+	healthy := []entry{
+		{provider: &fakeProvider{name: "a"}, weight: 5},
+		{provider: &fakeProvider{name: "b"}, weight: 3},
+		{provider: &fakeProvider{name: "c"}, weight: 1},
+	}
+
+	ordered := weightedOrder(healthy)
+	if len(ordered) != len(healthy) {
+		t.Fatalf("got %d providers, want %d", len(ordered), len(healthy))
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range ordered {
+		seen[p.Name()] = true
+	}
+	for _, e := range healthy {
+		if !seen[e.provider.Name()] {
+			t.Errorf("weightedOrder dropped provider %q", e.provider.Name())
+		}
+	}
+}
+
+func TestWeightedOrderIsProportional(t *testing.T) {
+	healthy := []entry{
+		{provider: &fakeProvider{name: "heavy"}, weight: 99},
+		{provider: &fakeProvider{name: "light"}, weight: 1},
+	}
+
+	firstCounts := map[string]int{}
+	const trials = 500
+	for i := 0; i < trials; i++ {
+		ordered := weightedOrder(healthy)
+		firstCounts[ordered[0].Name()]++
+	}
+
+	// With a 99:1 weight split, "heavy" should be picked first the
+	// overwhelming majority of the time but "light" should still win
+	// occasionally - this is a probabilistic selection, not a
+	// deterministic sort by weight descending.
+	if firstCounts["heavy"] < trials/2 {
+		t.Errorf("expected heavy-weighted provider to usually be picked first, got %d/%d", firstCounts["heavy"], trials)
+	}
+	if firstCounts["light"] == 0 {
+		t.Errorf("expected light-weighted provider to occasionally be picked first across %d trials, got 0", trials)
+	}
+}
+
+func TestWeightedOrderZeroWeightFallsBackToUniform(t *testing.T) {
+	healthy := []entry{
+		{provider: &fakeProvider{name: "a"}, weight: 0},
+		{provider: &fakeProvider{name: "b"}, weight: -1},
+	}
+
+	firstCounts := map[string]int{}
+	const trials = 200
+	for i := 0; i < trials; i++ {
+		ordered := weightedOrder(healthy)
+		if len(ordered) != 2 {
+			t.Fatalf("got %d providers, want 2", len(ordered))
+		}
+		firstCounts[ordered[0].Name()]++
+	}
+
+	if firstCounts["a"] == 0 || firstCounts["b"] == 0 {
+		t.Errorf("expected both non-positive-weight providers to be picked first sometimes, got %v", firstCounts)
+	}
+}
+
+func TestRouterAnalyzeFailsOverToNextProvider(t *testing.T) {
+	r := NewRouter(StrategyPriority)
+	r.Register(&fakeProvider{name: "broken", err: errors.New("boom")}, 1)
+	r.Register(&fakeProvider{name: "ok"}, 1)
+
+	result, err := r.Analyze(context.Background(), "diff", "")
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if result.Provider != "ok" {
+		t.Errorf("got provider %q, want %q", result.Provider, "ok")
+	}
+}
+
+func TestRouterAnalyzeAllProvidersFail(t *testing.T) {
+	r := NewRouter(StrategyPriority)
+	r.Register(&fakeProvider{name: "a", err: errors.New("boom")}, 1)
+	r.Register(&fakeProvider{name: "b", err: errors.New("boom")}, 1)
+
+	if _, err := r.Analyze(context.Background(), "diff", ""); err == nil {
+		t.Fatal("expected an error when every provider fails")
+	}
+}
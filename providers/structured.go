@@ -0,0 +1,29 @@
+package providers
+
+import (
+	"context"
+
+	"github.com/kowalski-ai/api/review"
+)
+
+// StructuredProvider is implemented by providers that can return a
+// structured review.Review instead of a free-form string, via
+// function-calling / tool-use.
+type StructuredProvider interface {
+	Provider
+
+	// AnalyzeStructured sends diff to the underlying model and returns
+	// its review as a validated review.Review, retrying once with a
+	// repair prompt if the first response fails validation.
+	AnalyzeStructured(ctx context.Context, diff string) (review.Review, error)
+}
+
+// maxRepairAttempts bounds how many times a provider retries after the
+// model returns a response that fails review.Validate.
+const maxRepairAttempts = 1
+
+// repairPrompt is appended to the diff prompt on a retry so the model
+// can see what was wrong with its previous attempt.
+func repairPrompt(diff string, validationErr error) string {
+	return diff + "\n\nYour previous response was invalid: " + validationErr.Error() + "\nPlease resubmit a corrected review."
+}
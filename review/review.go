@@ -0,0 +1,29 @@
+// Package review defines the structured code-review shape that replaces
+// free-form analysis strings, plus the JSON Schema used to coax models
+// into returning it via function-calling / tool-use.
+package review
+
+// Review is a model's structured assessment of a PR's diff.
+type Review struct {
+	Summary          string    `json:"summary"`
+	RiskScore        int       `json:"risk_score"` // 0 (trivial) .. 10 (severe)
+	Findings         []Finding `json:"findings"`
+	SuggestedPatches []Patch   `json:"suggested_patches"`
+	TestCoverageGaps []string  `json:"test_coverage_gaps"`
+}
+
+// Finding is one issue raised against a specific file and line range.
+type Finding struct {
+	File        string `json:"file"`
+	StartLine   int    `json:"start_line"`
+	EndLine     int    `json:"end_line"`
+	Severity    string `json:"severity"` // "info", "low", "medium", "high", "critical"
+	Category    string `json:"category"` // "bug", "security", "style", "performance", ...
+	Description string `json:"description"`
+}
+
+// Patch is a suggested fix for a file.
+type Patch struct {
+	File string `json:"file"`
+	Diff string `json:"diff"`
+}
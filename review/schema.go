@@ -0,0 +1,98 @@
+package review
+
+import (
+	"encoding/json"
+
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+// Name is the function/tool name models are asked to call to return a
+// Review.
+const Name = "submit_code_review"
+
+// Description is the function/tool description shown to the model.
+const Description = "Submit a structured code review of the analyzed diff."
+
+// Definition returns the JSON Schema describing Review, suitable for
+// OpenAI's response_format/json_schema or function-calling mode. It
+// returns a pointer because ChatCompletionResponseFormatJSONSchema.Schema
+// is a json.Marshaler, which jsonschema.Definition only satisfies via a
+// pointer receiver.
+//
+// OpenAI's strict mode requires every property to be listed in its
+// object's `required` array and every object to set
+// `additionalProperties: false`; there's no separate "optional"
+// concept. Fields that are genuinely optional (end_line) are still
+// required but marked Nullable so the model can satisfy the schema
+// with an explicit null instead of omitting the key.
+func Definition() *jsonschema.Definition {
+	finding := jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"file":        {Type: jsonschema.String},
+			"start_line":  {Type: jsonschema.Integer},
+			"end_line":    {Type: jsonschema.Integer, Nullable: true, Description: "null if the finding applies to a single line"},
+			"severity":    {Type: jsonschema.String, Enum: []string{"info", "low", "medium", "high", "critical"}},
+			"category":    {Type: jsonschema.String},
+			"description": {Type: jsonschema.String},
+		},
+		Required:             []string{"file", "start_line", "end_line", "severity", "category", "description"},
+		AdditionalProperties: false,
+	}
+
+	patch := jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"file": {Type: jsonschema.String},
+			"diff": {Type: jsonschema.String},
+		},
+		Required:             []string{"file", "diff"},
+		AdditionalProperties: false,
+	}
+
+	return &jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"summary":    {Type: jsonschema.String},
+			"risk_score": {Type: jsonschema.Integer, Description: "0 (trivial) to 10 (severe)"},
+			"findings": {
+				Type:  jsonschema.Array,
+				Items: &finding,
+			},
+			"suggested_patches": {
+				Type:  jsonschema.Array,
+				Items: &patch,
+			},
+			"test_coverage_gaps": {
+				Type:  jsonschema.Array,
+				Items: &jsonschema.Definition{Type: jsonschema.String},
+			},
+		},
+		Required:             []string{"summary", "risk_score", "findings", "suggested_patches", "test_coverage_gaps"},
+		AdditionalProperties: false,
+	}
+}
+
+// AnthropicInputSchema returns the same schema as a plain map, the shape
+// Anthropic's tool_use `input_schema` expects.
+func AnthropicInputSchema() (map[string]any, error) {
+	encoded, err := json.Marshal(Definition())
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(encoded, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Parse decodes raw JSON (the model's function/tool-call arguments) into
+// a Review.
+func Parse(raw []byte) (Review, error) {
+	var r Review
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return Review{}, err
+	}
+	return r, nil
+}
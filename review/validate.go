@@ -0,0 +1,40 @@
+package review
+
+import "fmt"
+
+var validSeverities = map[string]bool{
+	"info": true, "low": true, "medium": true, "high": true, "critical": true,
+}
+
+// Validate checks that r satisfies the constraints Definition encodes
+// that a bare JSON unmarshal can't enforce (enums, ranges, required
+// nested fields). Callers use this to decide whether to retry with a
+// repair prompt.
+func Validate(r Review) error {
+	if r.Summary == "" {
+		return fmt.Errorf("review: summary is required")
+	}
+	if r.RiskScore < 0 || r.RiskScore > 10 {
+		return fmt.Errorf("review: risk_score must be between 0 and 10, got %d", r.RiskScore)
+	}
+	for i, f := range r.Findings {
+		if f.File == "" {
+			return fmt.Errorf("review: findings[%d].file is required", i)
+		}
+		if !validSeverities[f.Severity] {
+			return fmt.Errorf("review: findings[%d].severity %q is not a recognized severity", i, f.Severity)
+		}
+		if f.Category == "" {
+			return fmt.Errorf("review: findings[%d].category is required", i)
+		}
+		if f.Description == "" {
+			return fmt.Errorf("review: findings[%d].description is required", i)
+		}
+	}
+	for i, p := range r.SuggestedPatches {
+		if p.File == "" {
+			return fmt.Errorf("review: suggested_patches[%d].file is required", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package webhook
+
+import "github.com/kowalski-ai/api/diffchunker"
+
+// PositionMap resolves a (file, line) pair from a review finding to the
+// "position" GitHub's legacy review-comments API expects: a 1-based
+// offset into that file's unified diff hunks, counting every added,
+// removed, and context line starting from the first "@@" hunk header.
+type PositionMap struct {
+	positions map[string]map[int]int // path -> new-file line -> diff position
+}
+
+// BuildPositionMap walks files' parsed hunks and records, for every
+// added or context line, the diff position its new-file line number
+// maps to.
+func BuildPositionMap(files []diffchunker.FileDiff) *PositionMap {
+	pm := &PositionMap{positions: make(map[string]map[int]int)}
+	for _, f := range files {
+		lines := make(map[int]int)
+		position := 0
+		for _, h := range f.Hunks {
+			line := h.StartLine
+			for i, raw := range splitLines(h.Body) {
+				position++
+				if i == 0 {
+					// the "@@ ... @@" header line itself; no new-file line yet
+					continue
+				}
+				if len(raw) == 0 {
+					line++
+					continue
+				}
+				switch raw[0] {
+				case '-':
+					// removed line: consumes a position, not a new-file line
+				case '+':
+					lines[line] = position
+					line++
+				default:
+					lines[line] = position
+					line++
+				}
+			}
+		}
+		pm.positions[f.Path] = lines
+	}
+	return pm
+}
+
+// Position returns the diff position for path's new-file line, and
+// whether that line appears in the diff at all (context/added lines
+// only — GitHub can't anchor a comment to a removed or unchanged line
+// outside the diff).
+func (pm *PositionMap) Position(path string, line int) (int, bool) {
+	lines, ok := pm.positions[path]
+	if !ok {
+		return 0, false
+	}
+	pos, ok := lines[line]
+	return pos, ok
+}
+
+func splitLines(body string) []string {
+	var lines []string
+	start := 0
+	for i := 0; i < len(body); i++ {
+		if body[i] == '\n' {
+			lines = append(lines, body[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(body) {
+		lines = append(lines, body[start:])
+	}
+	return lines
+}
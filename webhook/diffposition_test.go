@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/kowalski-ai/api/diffchunker"
+)
+
+const samplePatch = `diff --git a/foo.go b/foo.go
+@@ -1,3 +1,4 @@
+ package foo
++
+ func Foo() {
+-	return
++	return nil
+ }
+`
+
+func TestBuildPositionMap(t *testing.T) {
+	files := diffchunker.ParseDiff(samplePatch)
+	pm := BuildPositionMap(files)
+
+	cases := []struct {
+		line     int
+		wantPos  int
+		wantOK   bool
+		scenario string
+	}{
+		{1, 2, true, "first context line"},
+		{2, 3, true, "added blank line"},
+		{3, 4, true, "context line before the changed body"},
+		{4, 6, true, "replacement addition line"},
+		{5, 7, true, "trailing context line"},
+	}
+
+	for _, c := range cases {
+		pos, ok := pm.Position("foo.go", c.line)
+		if ok != c.wantOK {
+			t.Errorf("%s: line %d: got ok=%v, want %v", c.scenario, c.line, ok, c.wantOK)
+			continue
+		}
+		if pos != c.wantPos {
+			t.Errorf("%s: line %d: got position %d, want %d", c.scenario, c.line, pos, c.wantPos)
+		}
+	}
+}
+
+func TestBuildPositionMapRemovedLineNotAddressable(t *testing.T) {
+	files := diffchunker.ParseDiff(samplePatch)
+	pm := BuildPositionMap(files)
+
+	// The removed "return" line never existed in the new file, so it
+	// must not be assigned a new-file line number at all: line 4 is
+	// claimed by the replacement "return nil" addition instead.
+	if pos, ok := pm.Position("foo.go", 4); !ok || pos != 6 {
+		t.Fatalf("line 4: got position %d (ok=%v), want 6 (the addition, not the removed line at 5)", pos, ok)
+	}
+	if _, ok := pm.Position("foo.go", 100); ok {
+		t.Fatal("expected an out-of-range line to have no diff position")
+	}
+}
+
+func TestBuildPositionMapUnknownFile(t *testing.T) {
+	pm := BuildPositionMap(diffchunker.ParseDiff(samplePatch))
+
+	if _, ok := pm.Position("nonexistent.go", 1); ok {
+		t.Fatal("expected a file absent from the diff to have no positions")
+	}
+}
@@ -0,0 +1,59 @@
+package webhook
+
+import "strings"
+
+// PullRequestEvent is the subset of GitHub's `pull_request` webhook
+// payload this package cares about.
+type PullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Title string `json:"title"`
+		Head  struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	} `json:"pull_request"`
+	Repository struct {
+		Name  string `json:"name"`
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"repository"`
+}
+
+// reviewableActions are the `pull_request` actions worth re-analyzing;
+// other actions (closed, labeled, review_requested, ...) are ignored.
+var reviewableActions = map[string]bool{
+	"opened":      true,
+	"synchronize": true,
+}
+
+// ShouldReview reports whether e warrants running analysis: its action
+// is one we care about, and it isn't bypassed via a "[skip-review]"
+// title marker or "skip-review" label.
+func (e PullRequestEvent) ShouldReview() bool {
+	if !reviewableActions[e.Action] {
+		return false
+	}
+	if strings.Contains(e.PullRequest.Title, "[skip-review]") {
+		return false
+	}
+	for _, label := range e.PullRequest.Labels {
+		if label.Name == "skip-review" {
+			return false
+		}
+	}
+	return true
+}
+
+// Owner is the repository owner login.
+func (e PullRequestEvent) Owner() string { return e.Repository.Owner.Login }
+
+// Repo is the repository name.
+func (e PullRequestEvent) Repo() string { return e.Repository.Name }
+
+// HeadSHA is the PR's current head commit SHA.
+func (e PullRequestEvent) HeadSHA() string { return e.PullRequest.Head.SHA }
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kowalski-ai/api/review"
+)
+
+const githubAPIBase = "https://api.github.com"
+
+// reviewComment is one entry in the GitHub "create a review" request's
+// comments array, anchored to a diff position rather than a line
+// number (GitHub's legacy position-based review API).
+type reviewComment struct {
+	Path     string `json:"path"`
+	Position int    `json:"position"`
+	Body     string `json:"body"`
+}
+
+type createReviewRequest struct {
+	Body     string          `json:"body"`
+	Event    string          `json:"event"`
+	Comments []reviewComment `json:"comments"`
+}
+
+// PostReview submits r as a pull request review on GitHub, using
+// positions to anchor each finding that falls on a line present in the
+// diff. Findings that can't be mapped to a diff position (e.g. they
+// cite a removed or out-of-diff line) are folded into the review's
+// top-level body instead of being dropped.
+func PostReview(ctx context.Context, token, owner, repo string, number int, r review.Review, positions *PositionMap) error {
+	req := createReviewRequest{
+		Body:  summaryBody(r),
+		Event: "COMMENT",
+	}
+
+	for _, f := range r.Findings {
+		pos, ok := positions.Position(f.File, f.StartLine)
+		if !ok {
+			req.Body += fmt.Sprintf("\n\n**%s** (%s:%d, %s): %s", f.Severity, f.File, f.StartLine, f.Category, f.Description)
+			continue
+		}
+		req.Comments = append(req.Comments, reviewComment{
+			Path:     f.File,
+			Position: pos,
+			Body:     fmt.Sprintf("**%s** (%s): %s", f.Severity, f.Category, f.Description),
+		})
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("webhook: marshal review: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/reviews", githubAPIBase, owner, repo, number)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: build request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	httpReq.Header.Set("Accept", "application/vnd.github+json")
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("webhook: post review: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: post review: unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func summaryBody(r review.Review) string {
+	return fmt.Sprintf("**Automated review** (risk score %d/10)\n\n%s", r.RiskScore, r.Summary)
+}
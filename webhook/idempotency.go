@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SeenTracker records the head SHA last reviewed for each PR, so a
+// webhook retry (or a second event for a SHA we already reviewed)
+// doesn't trigger a duplicate review.
+type SeenTracker struct {
+	mu   sync.Mutex
+	seen map[string]string // "owner/repo#number" -> last reviewed SHA
+}
+
+// NewSeenTracker returns an empty SeenTracker.
+func NewSeenTracker() *SeenTracker {
+	return &SeenTracker{seen: make(map[string]string)}
+}
+
+func prKey(owner, repo string, number int) string {
+	return fmt.Sprintf("%s/%s#%d", owner, repo, number)
+}
+
+// AlreadyReviewed reports whether sha is already the last-reviewed SHA
+// recorded for this PR.
+func (s *SeenTracker) AlreadyReviewed(owner, repo string, number int, sha string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seen[prKey(owner, repo, number)] == sha
+}
+
+// MarkReviewed records sha as the last-reviewed SHA for this PR.
+func (s *SeenTracker) MarkReviewed(owner, repo string, number int, sha string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seen[prKey(owner, repo, number)] = sha
+}
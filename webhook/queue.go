@@ -0,0 +1,41 @@
+package webhook
+
+import "sync"
+
+// Queue is a bounded worker pool for running review jobs off the
+// webhook HTTP handler's goroutine, so a burst of pull_request events
+// doesn't spawn unbounded concurrent analyses.
+type Queue struct {
+	jobs chan func()
+	wg   sync.WaitGroup
+}
+
+// NewQueue starts workers goroutines draining a job channel of size
+// backlog.
+func NewQueue(workers, backlog int) *Queue {
+	q := &Queue{jobs: make(chan func(), backlog)}
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+	return q
+}
+
+func (q *Queue) worker() {
+	defer q.wg.Done()
+	for job := range q.jobs {
+		job()
+	}
+}
+
+// Submit enqueues job to run asynchronously. It blocks if the backlog
+// is full.
+func (q *Queue) Submit(job func()) {
+	q.jobs <- job
+}
+
+// Stop closes the job channel and waits for in-flight jobs to finish.
+func (q *Queue) Stop() {
+	close(q.jobs)
+	q.wg.Wait()
+}
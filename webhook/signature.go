@@ -0,0 +1,31 @@
+// Package webhook implements the GitHub webhook receiver: signature
+// verification, event parsing, an async job queue, and posting the
+// resulting structured review back as inline PR comments.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// VerifySignature reports whether signatureHeader (the raw
+// "X-Hub-Signature-256" header value, "sha256=<hex>") is a valid HMAC of
+// payload under secret.
+func VerifySignature(secret, payload []byte, signatureHeader string) bool {
+	const prefix = "sha256="
+	if !strings.HasPrefix(signatureHeader, prefix) {
+		return false
+	}
+	expected, err := hex.DecodeString(strings.TrimPrefix(signatureHeader, prefix))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	actual := mac.Sum(nil)
+
+	return hmac.Equal(actual, expected)
+}
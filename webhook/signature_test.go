@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func sign(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignatureValid(t *testing.T) {
+	secret := []byte("shhh")
+	payload := []byte(`{"action":"opened"}`)
+
+	if !VerifySignature(secret, payload, sign(secret, payload)) {
+		t.Fatal("expected a correctly signed payload to verify")
+	}
+}
+
+func TestVerifySignatureWrongSecret(t *testing.T) {
+	payload := []byte(`{"action":"opened"}`)
+
+	if VerifySignature([]byte("shhh"), payload, sign([]byte("other"), payload)) {
+		t.Fatal("expected signature from a different secret to be rejected")
+	}
+}
+
+func TestVerifySignatureTamperedPayload(t *testing.T) {
+	secret := []byte("shhh")
+	payload := []byte(`{"action":"opened"}`)
+	header := sign(secret, payload)
+
+	if VerifySignature(secret, []byte(`{"action":"closed"}`), header) {
+		t.Fatal("expected a tampered payload to be rejected")
+	}
+}
+
+func TestVerifySignatureMissingPrefix(t *testing.T) {
+	secret := []byte("shhh")
+	payload := []byte(`{"action":"opened"}`)
+	header := sign(secret, payload)
+
+	if VerifySignature(secret, payload, header[len("sha256="):]) {
+		t.Fatal("expected a header without the sha256= prefix to be rejected")
+	}
+}
+
+func TestVerifySignatureInvalidHex(t *testing.T) {
+	if VerifySignature([]byte("shhh"), []byte("payload"), "sha256=not-hex") {
+		t.Fatal("expected undecodable hex to be rejected")
+	}
+}
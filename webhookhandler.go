@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/kowalski-ai/api/diffchunker"
+	"github.com/kowalski-ai/api/webhook"
+)
+
+// webhookQueue runs webhookHandler's async review jobs on a bounded
+// pool so a burst of pull_request deliveries doesn't spawn unbounded
+// concurrent analyses.
+var webhookQueue *webhook.Queue
+
+// webhookSeen tracks the last head SHA reviewed per PR, so a duplicate
+// delivery (or a second event for a SHA already reviewed) is a no-op.
+var webhookSeen = webhook.NewSeenTracker()
+
+// webhookHandler receives GitHub's `pull_request` webhook, verifies its
+// signature, and - if the event warrants it - enqueues an asynchronous
+// structured review that gets posted back as inline PR comments.
+func webhookHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	secret := cfgManager.Config().WebhookSecret
+	if secret == "" {
+		http.Error(w, "Webhook mode is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	if !webhook.VerifySignature([]byte(secret), body, r.Header.Get("X-Hub-Signature-256")) {
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var event webhook.PullRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "Invalid event payload", http.StatusBadRequest)
+		return
+	}
+
+	if !event.ShouldReview() {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	owner, repo, number, sha := event.Owner(), event.Repo(), event.Number, event.HeadSHA()
+	if webhookSeen.AlreadyReviewed(owner, repo, number, sha) {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	webhookQueue.Submit(func() {
+		if err := reviewAndPost(owner, repo, number, sha); err != nil {
+			log.Printf("webhook: review of %s/%s#%d failed: %v", owner, repo, number, err)
+		}
+	})
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// reviewAndPost fetches the PR diff, runs a structured review, and
+// posts it back to GitHub as an inline review. It's run off the
+// webhook goroutine via webhookQueue.
+func reviewAndPost(owner, repo string, number int, headSHA string) error {
+	diff, err := fetchPRChanges(owner, repo, number)
+	if err != nil {
+		return err
+	}
+
+	policy := cfgManager.PolicyFor(owner, repo)
+	files := diffchunker.FilterIgnored(diffchunker.ParseDiff(diff), policy.IgnoredPaths)
+
+	ctx := context.Background()
+	result, err := chunkedStructuredAnalysis(ctx, router, diff, policy.PreferredModel, 0, 0, policy, nil)
+	if err != nil {
+		return err
+	}
+
+	positions := webhook.BuildPositionMap(files)
+	if err := webhook.PostReview(ctx, cfgManager.Config().GithubToken, owner, repo, number, result.Review, positions); err != nil {
+		return err
+	}
+
+	webhookSeen.MarkReviewed(owner, repo, number, headSHA)
+	return nil
+}